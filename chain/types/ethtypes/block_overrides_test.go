@@ -0,0 +1,71 @@
+package ethtypes
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestBlockOverridesMarshalUsesFinalizedNames(t *testing.T) {
+	fee := EthUint64(7)
+	random := EthHash{0x01}
+	coinbase := EthAddress{0x02}
+
+	b, err := json.Marshal(BlockOverrides{BaseFee: &fee, Random: &random, Coinbase: &coinbase})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		t.Fatalf("Unmarshal raw: %v", err)
+	}
+	for _, want := range []string{"baseFeePerGas", "prevRandao", "feeRecipient"} {
+		if _, ok := raw[want]; !ok {
+			t.Fatalf("expected marshaled output to contain %q, got %s", want, b)
+		}
+	}
+}
+
+func TestBlockOverridesUnmarshalAcceptsLegacyNames(t *testing.T) {
+	input := `{"Number":"0x1","Difficulty":"0x2","Time":"0x3","GasLimit":"0x4","Coinbase":"0x0000000000000000000000000000000000000005","Random":"0x0000000000000000000000000000000000000000000000000000000000000006","BaseFee":"0x7"}`
+
+	var diff BlockOverrides
+	if err := json.Unmarshal([]byte(input), &diff); err != nil {
+		t.Fatalf("Unmarshal legacy: %v", err)
+	}
+
+	if diff.Number == nil || diff.Number.Int.Cmp(big.NewInt(1)) != 0 {
+		t.Fatalf("expected Number=1, got %v", diff.Number)
+	}
+	if diff.Difficulty == nil || diff.Difficulty.Int.Cmp(big.NewInt(2)) != 0 {
+		t.Fatalf("expected Difficulty=2, got %v", diff.Difficulty)
+	}
+	if diff.Time == nil || *diff.Time != 3 {
+		t.Fatalf("expected Time=3, got %v", diff.Time)
+	}
+	if diff.GasLimit == nil || *diff.GasLimit != 4 {
+		t.Fatalf("expected GasLimit=4, got %v", diff.GasLimit)
+	}
+	if diff.Coinbase == nil {
+		t.Fatalf("expected Coinbase to be set")
+	}
+	if diff.Random == nil {
+		t.Fatalf("expected Random to be set")
+	}
+	if diff.BaseFee == nil || *diff.BaseFee != 7 {
+		t.Fatalf("expected BaseFee=7, got %v", diff.BaseFee)
+	}
+}
+
+func TestBlockOverridesUnmarshalPrefersFinalizedNames(t *testing.T) {
+	input := `{"Time":"0x1","time":"0x2"}`
+
+	var diff BlockOverrides
+	if err := json.Unmarshal([]byte(input), &diff); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if diff.Time == nil || *diff.Time != 2 {
+		t.Fatalf("expected finalized name to win with Time=2, got %v", diff.Time)
+	}
+}
@@ -0,0 +1,66 @@
+package ethtypes
+
+// ForkConfig holds the activation timestamps of the Ethereum hardforks that
+// matter to the EVM opcode set. Unlike the pre-Merge forks, these are keyed
+// by block timestamp rather than block number, matching how go-ethereum and
+// the execution-apis spec schedule them.
+type ForkConfig struct {
+	ShanghaiTime *uint64
+	CancunTime   *uint64
+}
+
+// Mainnet Shanghai and Cancun activation times, used to seed
+// DefaultForkConfig below.
+var (
+	mainnetShanghaiTime uint64 = 1681338455 // 2023-04-12T10:27:35Z
+	mainnetCancunTime   uint64 = 1710338135 // 2024-03-13T13:55:35Z
+)
+
+// DefaultForkConfig is used by BlockOverrides.Apply, which doesn't take a
+// ForkConfig of its own. It's seeded with mainnet's fork schedule; callers
+// tracing a different network, or who want BlockContext.Fork resolved
+// against a specific chain config, should call BlockOverrides.ApplyWithFork
+// with their own ForkConfig instead of mutating this global.
+var DefaultForkConfig = ForkConfig{
+	ShanghaiTime: &mainnetShanghaiTime,
+	CancunTime:   &mainnetCancunTime,
+}
+
+// IsShanghai reports whether the Shanghai fork (PUSH0, withdrawals) is active
+// at the given block time.
+func (c *ForkConfig) IsShanghai(time uint64) bool {
+	return isTimestampForked(c.ShanghaiTime, time)
+}
+
+// IsCancun reports whether the Cancun fork (TSTORE/TLOAD, blobs) is active at
+// the given block time.
+func (c *ForkConfig) IsCancun(time uint64) bool {
+	return isTimestampForked(c.CancunTime, time)
+}
+
+func isTimestampForked(fork *uint64, time uint64) bool {
+	return fork != nil && time >= *fork
+}
+
+// ForkID identifies which fork's opcode semantics a BlockContext should use.
+// It's derived from BlockContext.Time so that a traced call with an
+// overridden timestamp automatically exercises the right opcode set.
+type ForkID string
+
+const (
+	ForkPreShanghai ForkID = "pre-shanghai"
+	ForkShanghai    ForkID = "shanghai"
+	ForkCancun      ForkID = "cancun"
+)
+
+// Resolve returns the highest fork active at time under c.
+func (c *ForkConfig) Resolve(time uint64) ForkID {
+	switch {
+	case c.IsCancun(time):
+		return ForkCancun
+	case c.IsShanghai(time):
+		return ForkShanghai
+	default:
+		return ForkPreShanghai
+	}
+}
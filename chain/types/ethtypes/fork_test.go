@@ -0,0 +1,32 @@
+package ethtypes
+
+import "testing"
+
+func TestBlockOverridesApplyResolvesFork(t *testing.T) {
+	blockCtx := &BlockContext{}
+	preShanghai := EthUint64(1000)
+
+	(&BlockOverrides{Time: &preShanghai}).Apply(blockCtx)
+	if blockCtx.Fork != ForkPreShanghai {
+		t.Fatalf("expected pre-shanghai fork for timestamp %d, got %s", preShanghai, blockCtx.Fork)
+	}
+
+	cancun := EthUint64(mainnetCancunTime + 1)
+	(&BlockOverrides{Time: &cancun}).Apply(blockCtx)
+	if blockCtx.Fork != ForkCancun {
+		t.Fatalf("expected cancun fork for timestamp %d, got %s", cancun, blockCtx.Fork)
+	}
+}
+
+func TestBlockOverridesApplyWithForkUsesGivenConfig(t *testing.T) {
+	devnetShanghai := uint64(42)
+	forks := &ForkConfig{ShanghaiTime: &devnetShanghai}
+
+	blockCtx := &BlockContext{}
+	time := EthUint64(100)
+	(&BlockOverrides{Time: &time}).ApplyWithFork(blockCtx, forks)
+
+	if blockCtx.Fork != ForkShanghai {
+		t.Fatalf("expected shanghai fork under devnet ForkConfig, got %s", blockCtx.Fork)
+	}
+}
@@ -0,0 +1,163 @@
+package ethtypes
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// transferLogSentinelAddress is the address used as the emitter of synthetic
+// ERC-20 style Transfer logs when TraceTransfers is set on a SimulateV1
+// request. It matches the convention used by other multicall implementations
+// for representing native value transfers as logs, so client tooling can
+// recognize it without any extra configuration.
+var transferLogSentinelAddress = EthAddress{
+	0xee, 0xee, 0xee, 0xee, 0xee, 0xee, 0xee, 0xee, 0xee, 0xee,
+	0xee, 0xee, 0xee, 0xee, 0xee, 0xee, 0xee, 0xee, 0xee, 0xee,
+}
+
+// transferEventTopic is the keccak256 of Transfer(address,address,uint256),
+// used as topic[0] of the synthetic logs described above.
+var transferEventTopic = EthHash{
+	0xdd, 0xf2, 0x52, 0xad, 0x1b, 0xe2, 0xc8, 0x9b, 0x69, 0xc2, 0xb0, 0x68, 0xfc, 0x37, 0x8d, 0xaa,
+	0x95, 0x2b, 0xa7, 0xf1, 0x63, 0xc4, 0xa1, 0x16, 0x28, 0xf5, 0x5a, 0x4d, 0xf5, 0x23, 0xb3, 0xef,
+}
+
+// BlockStateCall describes one simulated block within a SimulateV1 request:
+// the header and account overrides to apply before execution, and the calls
+// to run against the resulting block context.
+type BlockStateCall struct {
+	BlockOverrides *BlockOverrides `json:"blockOverrides,omitempty"`
+	StateOverrides *StateOverride  `json:"stateOverrides,omitempty"`
+	Calls          []EthCall       `json:"calls"`
+}
+
+// SimulateV1 is the request body for the eth_simulateV1 ("multicall") API. The
+// listed BlockStateCalls are executed in order, with state changes made by
+// one block (nonce bumps, balance changes, storage writes) visible to the
+// next.
+type SimulateV1 struct {
+	BlockStateCalls []BlockStateCall `json:"blockStateCalls"`
+	// Validation, when false, skips the usual gas price / balance / nonce
+	// checks so callers can dry-run "what-if" scenarios that wouldn't
+	// otherwise validate.
+	Validation bool `json:"validation"`
+	// TraceTransfers causes every value transfer made via a TransferFunc to
+	// also emit a synthetic Transfer log from transferLogSentinelAddress, so
+	// internal ether flows are visible without a full tracer.
+	TraceTransfers bool `json:"traceTransfers"`
+}
+
+// CallResult is the outcome of a single EthCall executed as part of a
+// simulated block.
+type CallResult struct {
+	ReturnData EthBytes  `json:"returnData"`
+	Logs       []EthLog  `json:"logs"`
+	GasUsed    EthUint64 `json:"gasUsed"`
+	Status     EthUint64 `json:"status"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// SimulatedBlockResult bundles the synthesized header for one simulated block
+// together with the result of every call executed against it.
+type SimulatedBlockResult struct {
+	Number EthUint64    `json:"number"`
+	Time   EthUint64    `json:"timestamp"`
+	Calls  []CallResult `json:"calls"`
+}
+
+// CallExecutorFunc executes a single EthCall against the given block context
+// and overridden state, honoring validation the way the caller's EVM
+// normally would when validation is true. It's supplied by the package that
+// owns the actual EVM, so SimulateV1.Execute here stays pure orchestration:
+// sequencing blocks, threading state, and wrapping transfers for
+// TraceTransfers.
+type CallExecutorFunc func(blockCtx *BlockContext, state StateOverrideReader, call EthCall, validation bool) CallResult
+
+// Execute runs req's BlockStateCalls in order against base, deriving each
+// block's BlockContext from the previous one via NextBlockContext,
+// dispatching every call through exec, and honoring TraceTransfers/
+// Validation. State changes made by exec (nonce bumps, balance changes,
+// storage writes) are expected to land on base itself, so later blocks and
+// later calls within the same block see them.
+//
+// forks is the fork schedule to resolve each derived BlockContext.Fork
+// against; callers must supply the one for the chain they're actually
+// tracing (e.g. FEVM's, not mainnet Ethereum's DefaultForkConfig).
+func (req *SimulateV1) Execute(base StateDB, blockCtx *BlockContext, forks *ForkConfig, exec CallExecutorFunc, emitLog func(EthLog)) ([]SimulatedBlockResult, error) {
+	if exec == nil {
+		return nil, fmt.Errorf("ethtypes: SimulateV1.Execute requires a CallExecutorFunc")
+	}
+
+	// Captured once, outside the loop: NextBlockContext copies whatever
+	// Transfer is on cur into the next block, so wrapping cur.Transfer
+	// in-place every iteration would nest it one layer deeper per block
+	// and replay each transfer's log that many times over.
+	baseTransfer := blockCtx.Transfer
+
+	results := make([]SimulatedBlockResult, 0, len(req.BlockStateCalls))
+	cur := blockCtx
+	for _, block := range req.BlockStateCalls {
+		cur = NextBlockContext(cur, block.BlockOverrides, forks)
+		if req.TraceTransfers && emitLog != nil {
+			cur.Transfer = WithTransferLog(baseTransfer, emitLog)
+		}
+
+		reader := NewOverrideReader(base, block.StateOverrides)
+
+		blockResult := SimulatedBlockResult{
+			Number: EthUint64(cur.BlockNumber.Uint64()),
+			Time:   EthUint64(cur.Time),
+			Calls:  make([]CallResult, 0, len(block.Calls)),
+		}
+		for _, call := range block.Calls {
+			blockResult.Calls = append(blockResult.Calls, exec(cur, reader, call, req.Validation))
+		}
+		results = append(results, blockResult)
+	}
+
+	return results, nil
+}
+
+// NextBlockContext derives the BlockContext for the next simulated block,
+// applying overrides on top of prev and auto-incrementing Number and Time by
+// one when the caller didn't override them. BlockContext.Fork is resolved
+// against forks, not DefaultForkConfig, so callers tracing a non-mainnet
+// chain (e.g. FEVM) get the right opcode semantics for a bumped timestamp.
+func NextBlockContext(prev *BlockContext, overrides *BlockOverrides, forks *ForkConfig) *BlockContext {
+	next := *prev
+	next.BlockNumber = new(big.Int).Add(prev.BlockNumber, big.NewInt(1))
+	next.Time = prev.Time + 1
+
+	overrides.ApplyWithFork(&next, forks)
+
+	// ApplyWithFork only re-resolves Fork when overrides explicitly set
+	// Time; Time always changes here via the auto-increment above, so
+	// Fork needs to be resolved unconditionally against the final value.
+	next.Fork = forks.Resolve(next.Time)
+
+	return &next
+}
+
+// WithTransferLog wraps a TransferFunc so that, in addition to performing the
+// transfer, it records a synthetic ERC-20 style Transfer(from, to, value) log
+// via emit. Used when SimulateV1.TraceTransfers is set.
+func WithTransferLog(transfer TransferFunc, emit func(EthLog)) TransferFunc {
+	return func(db StateDB, from, to EthAddress, amount *big.Int) {
+		transfer(db, from, to, amount)
+
+		value := EthHash{}
+		amount.FillBytes(value[:])
+
+		emit(EthLog{
+			Address: transferLogSentinelAddress,
+			Topics:  []EthHash{transferEventTopic, addressToHash(from), addressToHash(to)},
+			Data:    EthBytes(value[:]),
+		})
+	}
+}
+
+func addressToHash(addr EthAddress) EthHash {
+	var h EthHash
+	copy(h[32-len(addr):], addr[:])
+	return h
+}
@@ -0,0 +1,79 @@
+package ethtypes
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestSimulateV1ExecuteThreadsBlocksAndCalls(t *testing.T) {
+	base := &BlockContext{
+		BlockNumber: big.NewInt(10),
+		Time:        1000,
+		Transfer:    func(StateDB, EthAddress, EthAddress, *big.Int) {},
+	}
+
+	var seenValidation []bool
+	var seenNumbers []uint64
+	var seenForks []ForkID
+	var emitted []EthLog
+
+	exec := CallExecutorFunc(func(blockCtx *BlockContext, state StateOverrideReader, call EthCall, validation bool) CallResult {
+		seenValidation = append(seenValidation, validation)
+		seenNumbers = append(seenNumbers, blockCtx.BlockNumber.Uint64())
+		seenForks = append(seenForks, blockCtx.Fork)
+		blockCtx.Transfer(nil, EthAddress{0x01}, EthAddress{0x02}, big.NewInt(5))
+		return CallResult{Status: 1}
+	})
+
+	req := &SimulateV1{
+		BlockStateCalls: []BlockStateCall{
+			{Calls: []EthCall{{}}},
+			{Calls: []EthCall{{}, {}}},
+		},
+		Validation:     false,
+		TraceTransfers: true,
+	}
+
+	// A devnet fork schedule, deliberately different from
+	// DefaultForkConfig's mainnet timestamps, to prove Execute actually
+	// threads it through rather than silently resolving Fork against
+	// mainnet Ethereum.
+	devnetShanghai := uint64(1000)
+	forks := &ForkConfig{ShanghaiTime: &devnetShanghai}
+
+	results, err := req.Execute(nil, base, forks, exec, func(l EthLog) { emitted = append(emitted, l) })
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 simulated blocks, got %d", len(results))
+	}
+	if len(results[0].Calls) != 1 || len(results[1].Calls) != 2 {
+		t.Fatalf("unexpected call counts: %+v", results)
+	}
+	if results[0].Number != 11 || results[1].Number != 12 {
+		t.Fatalf("expected auto-incrementing block numbers, got %d, %d", results[0].Number, results[1].Number)
+	}
+	if len(seenValidation) != 3 || seenValidation[0] {
+		t.Fatalf("expected Validation=false to reach every call, got %v", seenValidation)
+	}
+	if len(emitted) != 3 {
+		t.Fatalf("expected a synthetic transfer log per call, got %d", len(emitted))
+	}
+	if emitted[0].Address != transferLogSentinelAddress {
+		t.Fatalf("expected synthetic log from sentinel address, got %v", emitted[0].Address)
+	}
+	for _, f := range seenForks {
+		if f != ForkShanghai {
+			t.Fatalf("expected Fork resolved against the supplied devnet ForkConfig, got %v", seenForks)
+		}
+	}
+}
+
+func TestSimulateV1ExecuteRequiresExecutor(t *testing.T) {
+	req := &SimulateV1{}
+	if _, err := req.Execute(nil, &BlockContext{BlockNumber: big.NewInt(0)}, nil, nil, nil); err == nil {
+		t.Fatalf("expected error when exec is nil")
+	}
+}
@@ -0,0 +1,160 @@
+package ethtypes
+
+import "math/big"
+
+// StateOverrideReader exposes account state the way StateDB does, but with a
+// StateOverride layered on top. It never mutates the underlying state, so the
+// same base state can back many concurrent, differently-overridden calls
+// without copying.
+type StateOverrideReader interface {
+	GetBalance(addr EthAddress) *big.Int
+	GetNonce(addr EthAddress) uint64
+	GetCode(addr EthAddress) []byte
+	GetState(addr EthAddress, slot EthHash) EthHash
+	// GetCodeHash returns the account's overridden code hash, if the caller
+	// supplied one via OverrideAccount.CodeHash, or nil if it should be
+	// recomputed from GetCode as usual.
+	GetCodeHash(addr EthAddress) *EthHash
+	// HasOverride reports whether addr has any override applied at all,
+	// including an empty one, a precompile injection/removal, or being the
+	// target address of a MovePrecompileTo redirection.
+	HasOverride(addr EthAddress) bool
+}
+
+// overrideReader layers a StateOverride on top of a base StateDB.
+type overrideReader struct {
+	base      StateDB
+	overrides *StateOverride
+	byAddr    map[EthAddress]OverrideAccount
+
+	// precompiles is overrides.Precompiles, or nil. A nil value for a given
+	// address means "remove whatever precompile normally lives there".
+	precompiles map[EthAddress]*EthBytes
+	// movedPrecompileFrom maps a MovePrecompileTo target address back to the
+	// original address whose precompile implementation should now be served
+	// there.
+	movedPrecompileFrom map[EthAddress]EthAddress
+}
+
+// NewOverrideReader returns a StateOverrideReader that reads from base except
+// where overrides says otherwise. overrides may be nil, in which case every
+// read simply passes through to base.
+func NewOverrideReader(base StateDB, overrides *StateOverride) StateOverrideReader {
+	r := &overrideReader{base: base, overrides: overrides}
+
+	if overrides != nil {
+		r.byAddr = make(map[EthAddress]OverrideAccount, len(overrides.Accounts))
+		r.movedPrecompileFrom = make(map[EthAddress]EthAddress)
+		for hash, acct := range overrides.Accounts {
+			addr := EthAddress(truncateHashToAddress(hash))
+			r.byAddr[addr] = acct
+			if acct.MovePrecompileTo != nil {
+				r.movedPrecompileFrom[*acct.MovePrecompileTo] = addr
+			}
+		}
+		r.precompiles = overrides.Precompiles
+	}
+
+	return r
+}
+
+func (r *overrideReader) lookup(addr EthAddress) (OverrideAccount, bool) {
+	acct, ok := r.byAddr[addr]
+	return acct, ok
+}
+
+func (r *overrideReader) HasOverride(addr EthAddress) bool {
+	if _, ok := r.lookup(addr); ok {
+		return true
+	}
+	if _, ok := r.precompiles[addr]; ok {
+		return true
+	}
+	_, ok := r.movedPrecompileFrom[addr]
+	return ok
+}
+
+func (r *overrideReader) GetBalance(addr EthAddress) *big.Int {
+	if acct, ok := r.lookup(addr); ok && acct.Balance != nil && *acct.Balance != nil {
+		return (*acct.Balance).Int
+	}
+	return r.base.GetBalance(addr)
+}
+
+func (r *overrideReader) GetNonce(addr EthAddress) uint64 {
+	if acct, ok := r.lookup(addr); ok && acct.Nonce != nil {
+		return uint64(*acct.Nonce)
+	}
+	return r.base.GetNonce(addr)
+}
+
+// GetCode resolves the code at addr, honoring (in priority order) a wholesale
+// Precompiles injection/removal, a MovePrecompileTo redirection landing on
+// addr, an explicit OverrideAccount.Code, and finally falling back to base.
+func (r *overrideReader) GetCode(addr EthAddress) []byte {
+	if code, ok := r.precompiles[addr]; ok {
+		if code == nil {
+			return nil
+		}
+		return []byte(*code)
+	}
+
+	if source, ok := r.movedPrecompileFrom[addr]; ok {
+		return r.base.GetCode(source)
+	}
+
+	acct, ok := r.lookup(addr)
+	if !ok {
+		return r.base.GetCode(addr)
+	}
+
+	if acct.Code != nil {
+		return []byte(*acct.Code)
+	}
+
+	if acct.MovePrecompileTo != nil {
+		// The precompile that used to live here has moved out; addr is now
+		// a normal EOA/contract with no code of its own.
+		return nil
+	}
+
+	return r.base.GetCode(addr)
+}
+
+// GetCodeHash returns the overridden code hash for addr, if any.
+func (r *overrideReader) GetCodeHash(addr EthAddress) *EthHash {
+	if acct, ok := r.lookup(addr); ok && acct.CodeHash != nil {
+		return acct.CodeHash
+	}
+	return nil
+}
+
+// GetState resolves slot for addr, honoring the mutual exclusion between
+// State (a full replacement of the account's storage) and StateDiff (a
+// sparse patch over the base storage).
+func (r *overrideReader) GetState(addr EthAddress, slot EthHash) EthHash {
+	acct, ok := r.lookup(addr)
+	if !ok {
+		return r.base.GetState(addr, slot)
+	}
+
+	if acct.State != nil {
+		return (*acct.State)[slot]
+	}
+
+	if acct.StateDiff != nil {
+		if v, ok := (*acct.StateDiff)[slot]; ok {
+			return v
+		}
+	}
+
+	return r.base.GetState(addr, slot)
+}
+
+// truncateHashToAddress extracts the low 20 bytes of an EthHash-keyed
+// override, matching how StateOverride's map is keyed today.
+func truncateHashToAddress(h EthHash) [20]byte {
+	var addr [20]byte
+	copy(addr[:], h[12:])
+	return addr
+}
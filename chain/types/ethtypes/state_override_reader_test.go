@@ -0,0 +1,90 @@
+package ethtypes
+
+import (
+	"math/big"
+	"testing"
+)
+
+// fakeStateDB is a minimal, map-backed StateDB for exercising
+// StateOverrideReader's fall-through-to-base behavior without depending on
+// the real (unexported-here) StateDB implementation.
+type fakeStateDB struct {
+	code map[EthAddress][]byte
+}
+
+func (f *fakeStateDB) GetBalance(EthAddress) *big.Int       { return big.NewInt(0) }
+func (f *fakeStateDB) GetNonce(EthAddress) uint64           { return 0 }
+func (f *fakeStateDB) GetCode(addr EthAddress) []byte       { return f.code[addr] }
+func (f *fakeStateDB) GetState(EthAddress, EthHash) EthHash { return EthHash{} }
+
+func TestOverrideReaderPrecompileMove(t *testing.T) {
+	var original, moved EthAddress
+	original[19] = 0x01
+	moved[19] = 0x02
+
+	precompileCode := []byte{0xAA, 0xBB}
+	base := &fakeStateDB{code: map[EthAddress][]byte{original: precompileCode}}
+	movedTo := moved
+
+	overrides := &StateOverride{
+		Accounts: map[EthHash]OverrideAccount{
+			addressToHash(original): {MovePrecompileTo: &movedTo},
+		},
+	}
+
+	reader := NewOverrideReader(base, overrides)
+
+	if code := reader.GetCode(original); code != nil {
+		t.Fatalf("expected original precompile address to have no code after move, got %x", code)
+	}
+	if !reader.HasOverride(moved) {
+		t.Fatalf("expected moved-to address to report an override")
+	}
+	if got := reader.GetCode(moved); string(got) != string(precompileCode) {
+		t.Fatalf("expected moved-to address to serve the precompile's real implementation %x, got %x", precompileCode, got)
+	}
+}
+
+func TestOverrideReaderPrecompileInjectionAndRemoval(t *testing.T) {
+	var injected, removed EthAddress
+	injected[19] = 0x03
+	removed[19] = 0x04
+
+	code := EthBytes{0xCC}
+	overrides := &StateOverride{
+		Precompiles: map[EthAddress]*EthBytes{
+			injected: &code,
+			removed:  nil,
+		},
+	}
+
+	reader := NewOverrideReader(nil, overrides)
+
+	if got := reader.GetCode(injected); string(got) != string(code) {
+		t.Fatalf("expected injected precompile code %x, got %x", code, got)
+	}
+	if got := reader.GetCode(removed); got != nil {
+		t.Fatalf("expected removed precompile to have no code, got %x", got)
+	}
+	if !reader.HasOverride(injected) || !reader.HasOverride(removed) {
+		t.Fatalf("expected both injected and removed addresses to report an override")
+	}
+}
+
+func TestOverrideReaderCodeHash(t *testing.T) {
+	var addr EthAddress
+	addr[19] = 0x05
+	hash := EthHash{0x01}
+
+	overrides := &StateOverride{
+		Accounts: map[EthHash]OverrideAccount{
+			addressToHash(addr): {CodeHash: &hash},
+		},
+	}
+
+	reader := NewOverrideReader(nil, overrides)
+	got := reader.GetCodeHash(addr)
+	if got == nil || *got != hash {
+		t.Fatalf("expected overridden code hash %x, got %v", hash, got)
+	}
+}
@@ -0,0 +1,287 @@
+package ethtypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// Tracer is implemented by every native tracer. GetResult is called once
+// tracing is complete to obtain the value to stash on TxTraceResult.Result.
+type Tracer interface {
+	GetResult() (interface{}, error)
+}
+
+// FrameLogger is implemented by native tracers that observe EVM call frames
+// as they're entered and exited. The tracing EVM is expected to call
+// EnterFrame once per CALL/CREATE (and their variants) and ExitFrame exactly
+// once for each matching EnterFrame, even when the frame reverts.
+//
+// Not every Tracer is a FrameLogger: prestateTracer, for instance, observes
+// state reads/writes directly via RecordRead/RecordPost rather than call
+// frames. Callers that drive frame events should type-assert the Tracer
+// returned by NewTracer to FrameLogger and skip the calls when it's absent.
+type FrameLogger interface {
+	Tracer
+	EnterFrame(typ string, from, to EthAddress, input []byte, gas uint64, value *big.Int)
+	ExitFrame(gasUsed uint64, output []byte, err error)
+}
+
+// NewTracer builds the native tracer named by cfg.Tracer. It returns
+// (nil, nil) when cfg or cfg.Tracer is nil, since the caller should then fall
+// back to whatever default tracing behavior it already had.
+func NewTracer(cfg *TraceConfig) (Tracer, error) {
+	if cfg == nil || cfg.Tracer == nil {
+		return nil, nil
+	}
+
+	switch *cfg.Tracer {
+	case "callTracer":
+		return newCallTracer(), nil
+	case "prestateTracer":
+		diffMode, err := prestateTracerDiffMode(cfg.TracerConfig)
+		if err != nil {
+			return nil, err
+		}
+		return newPrestateTracer(diffMode), nil
+	default:
+		return nil, fmt.Errorf("ethtypes: unknown native tracer %q", *cfg.Tracer)
+	}
+}
+
+// CallFrame is one node of the call tree produced by callTracer.
+type CallFrame struct {
+	Type    string       `json:"type"`
+	From    EthAddress   `json:"from"`
+	To      *EthAddress  `json:"to,omitempty"`
+	Value   *EthBigInt   `json:"value,omitempty"`
+	Gas     EthUint64    `json:"gas"`
+	GasUsed EthUint64    `json:"gasUsed"`
+	Input   EthBytes     `json:"input"`
+	Output  EthBytes     `json:"output,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Calls   []*CallFrame `json:"calls,omitempty"`
+}
+
+// callTracer builds a CallFrame tree by hooking EnterFrame/ExitFrame on the
+// EVM interpreter. Gas is aggregated per frame, and a reverted subtree is
+// kept (with its error recorded) rather than discarded, matching upstream
+// go-ethereum's callTracer behavior.
+type callTracer struct {
+	root  *CallFrame
+	stack []*CallFrame
+}
+
+func newCallTracer() *callTracer {
+	return &callTracer{}
+}
+
+func (t *callTracer) EnterFrame(typ string, from, to EthAddress, input []byte, gas uint64, value *big.Int) {
+	frame := &CallFrame{
+		Type:  typ,
+		From:  from,
+		To:    &to,
+		Gas:   EthUint64(gas),
+		Input: EthBytes(input),
+	}
+	if value != nil {
+		frame.Value = &EthBigInt{Int: value}
+	}
+
+	if len(t.stack) == 0 {
+		t.root = frame
+	} else {
+		parent := t.stack[len(t.stack)-1]
+		parent.Calls = append(parent.Calls, frame)
+	}
+	t.stack = append(t.stack, frame)
+}
+
+func (t *callTracer) ExitFrame(gasUsed uint64, output []byte, err error) {
+	if len(t.stack) == 0 {
+		return
+	}
+
+	frame := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+
+	frame.GasUsed = EthUint64(gasUsed)
+	frame.Output = EthBytes(output)
+	if err != nil {
+		frame.Error = err.Error()
+	}
+}
+
+// GetResult returns the root of the call tree, for use as TxTraceResult.Result.
+func (t *callTracer) GetResult() (interface{}, error) {
+	if t.root == nil {
+		return nil, fmt.Errorf("ethtypes: callTracer produced no frames")
+	}
+	return t.root, nil
+}
+
+// PrestateAccount is the minimum account state callTracer's sibling,
+// prestateTracer, needs to replay a transaction: the fields actually read (or
+// changed, in diff mode) during execution.
+type PrestateAccount struct {
+	Balance *EthBigInt          `json:"balance,omitempty"`
+	Nonce   *EthUint64          `json:"nonce,omitempty"`
+	Code    *EthBytes           `json:"code,omitempty"`
+	Storage map[EthHash]EthHash `json:"storage,omitempty"`
+}
+
+// PrestateTracerResult is the "prestate" mode output of prestateTracer: the
+// state of every touched account immediately before execution.
+type PrestateTracerResult map[EthAddress]*PrestateAccount
+
+// PrestateTracerDiffResult is the "diff" mode output: only the fields that
+// changed, both before (Pre) and after (Post) execution.
+type PrestateTracerDiffResult struct {
+	Pre  PrestateTracerResult `json:"pre"`
+	Post PrestateTracerResult `json:"post"`
+}
+
+// prestateTracer records, for every account touched during execution, the
+// balance/nonce/code and every storage slot actually accessed. In diff mode
+// it additionally snapshots post-execution values and emits only the fields
+// that changed.
+type prestateTracer struct {
+	diffMode bool
+	pre      PrestateTracerResult
+	post     PrestateTracerResult
+}
+
+func newPrestateTracer(diffMode bool) *prestateTracer {
+	return &prestateTracer{
+		diffMode: diffMode,
+		pre:      PrestateTracerResult{},
+		post:     PrestateTracerResult{},
+	}
+}
+
+func prestateTracerDiffMode(tracerConfig []byte) (bool, error) {
+	if len(tracerConfig) == 0 {
+		return false, nil
+	}
+
+	var cfg struct {
+		DiffMode bool `json:"diffMode"`
+	}
+	if err := json.Unmarshal(tracerConfig, &cfg); err != nil {
+		return false, err
+	}
+	return cfg.DiffMode, nil
+}
+
+func (t *prestateTracer) account(set PrestateTracerResult, addr EthAddress) *PrestateAccount {
+	acct, ok := set[addr]
+	if !ok {
+		acct = &PrestateAccount{Storage: map[EthHash]EthHash{}}
+		set[addr] = acct
+	}
+	return acct
+}
+
+// RecordRead captures the pre-execution state of an account read during
+// tracing: its balance, nonce, code, and the given storage slot (if any).
+func (t *prestateTracer) RecordRead(addr EthAddress, balance *big.Int, nonce uint64, code []byte, slot *EthHash, value EthHash) {
+	acct := t.account(t.pre, addr)
+	if acct.Balance == nil && balance != nil {
+		acct.Balance = &EthBigInt{Int: balance}
+	}
+	if acct.Nonce == nil {
+		n := EthUint64(nonce)
+		acct.Nonce = &n
+	}
+	if acct.Code == nil && len(code) > 0 {
+		c := EthBytes(code)
+		acct.Code = &c
+	}
+	if slot != nil {
+		if _, seen := acct.Storage[*slot]; !seen {
+			acct.Storage[*slot] = value
+		}
+	}
+}
+
+// RecordPost captures the post-execution state of an account touched during
+// tracing. Only meaningful in diff mode.
+func (t *prestateTracer) RecordPost(addr EthAddress, balance *big.Int, nonce uint64, code []byte, slot *EthHash, value EthHash) {
+	if !t.diffMode {
+		return
+	}
+	acct := t.account(t.post, addr)
+	if balance != nil {
+		acct.Balance = &EthBigInt{Int: balance}
+	}
+	n := EthUint64(nonce)
+	acct.Nonce = &n
+	if len(code) > 0 {
+		c := EthBytes(code)
+		acct.Code = &c
+	}
+	if slot != nil {
+		acct.Storage[*slot] = value
+	}
+}
+
+// GetResult returns either a PrestateTracerResult (prestate mode) or a
+// PrestateTracerDiffResult (diff mode) with unchanged fields pruned.
+func (t *prestateTracer) GetResult() (interface{}, error) {
+	if !t.diffMode {
+		return t.pre, nil
+	}
+	return t.diffResult(), nil
+}
+
+// diffResult prunes both t.pre and t.post down to the accounts and fields
+// that actually changed. An account that was only read (e.g. a BALANCE check
+// on a third party) and never written via RecordPost has no entry in either
+// map, even though it's present in t.pre with every field populated.
+func (t *prestateTracer) diffResult() PrestateTracerDiffResult {
+	prunedPre := PrestateTracerResult{}
+	prunedPost := PrestateTracerResult{}
+
+	for addr, post := range t.post {
+		pre, ok := t.pre[addr]
+		if !ok {
+			prunedPost[addr] = post
+			continue
+		}
+
+		preDiff := &PrestateAccount{Storage: map[EthHash]EthHash{}}
+		postDiff := &PrestateAccount{Storage: map[EthHash]EthHash{}}
+		changed := false
+
+		if post.Balance != nil && (pre.Balance == nil || post.Balance.Int.Cmp(pre.Balance.Int) != 0) {
+			postDiff.Balance = post.Balance
+			preDiff.Balance = pre.Balance
+			changed = true
+		}
+		if post.Nonce != nil && (pre.Nonce == nil || *post.Nonce != *pre.Nonce) {
+			postDiff.Nonce = post.Nonce
+			preDiff.Nonce = pre.Nonce
+			changed = true
+		}
+		if post.Code != nil && (pre.Code == nil || string(*post.Code) != string(*pre.Code)) {
+			postDiff.Code = post.Code
+			preDiff.Code = pre.Code
+			changed = true
+		}
+		for slot, v := range post.Storage {
+			if pre.Storage[slot] != v {
+				postDiff.Storage[slot] = v
+				preDiff.Storage[slot] = pre.Storage[slot]
+				changed = true
+			}
+		}
+
+		if !changed {
+			continue
+		}
+		prunedPost[addr] = postDiff
+		prunedPre[addr] = preDiff
+	}
+
+	return PrestateTracerDiffResult{Pre: prunedPre, Post: prunedPost}
+}
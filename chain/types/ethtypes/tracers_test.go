@@ -0,0 +1,127 @@
+package ethtypes
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestNewTracerDispatch(t *testing.T) {
+	callName := "callTracer"
+	tr, err := NewTracer(&TraceConfig{Tracer: &callName})
+	if err != nil {
+		t.Fatalf("NewTracer(callTracer): %v", err)
+	}
+	if _, ok := tr.(FrameLogger); !ok {
+		t.Fatalf("callTracer result does not implement FrameLogger")
+	}
+
+	prestateName := "prestateTracer"
+	tr, err = NewTracer(&TraceConfig{Tracer: &prestateName})
+	if err != nil {
+		t.Fatalf("NewTracer(prestateTracer): %v", err)
+	}
+	if _, ok := tr.(FrameLogger); ok {
+		t.Fatalf("prestateTracer result unexpectedly implements FrameLogger")
+	}
+	if _, ok := tr.(Tracer); !ok {
+		t.Fatalf("prestateTracer result does not implement Tracer")
+	}
+
+	unknown := "notATracer"
+	if _, err := NewTracer(&TraceConfig{Tracer: &unknown}); err == nil {
+		t.Fatalf("expected error for unknown tracer name")
+	}
+}
+
+func TestCallTracerNestedFrames(t *testing.T) {
+	ct := newCallTracer()
+	from := EthAddress{0x01}
+	to := EthAddress{0x02}
+	inner := EthAddress{0x03}
+
+	ct.EnterFrame("CALL", from, to, nil, 100000, big.NewInt(0))
+	ct.EnterFrame("CALL", to, inner, nil, 50000, big.NewInt(0))
+	ct.ExitFrame(1000, nil, errors.New("execution reverted"))
+	ct.ExitFrame(2000, []byte{0xAA}, nil)
+
+	result, err := ct.GetResult()
+	if err != nil {
+		t.Fatalf("GetResult: %v", err)
+	}
+
+	root := result.(*CallFrame)
+	if root.GasUsed != 2000 || len(root.Calls) != 1 {
+		t.Fatalf("unexpected root frame: %+v", root)
+	}
+	child := root.Calls[0]
+	if child.GasUsed != 1000 || child.Error != "execution reverted" {
+		t.Fatalf("unexpected child frame: %+v", child)
+	}
+}
+
+func TestPrestateTracerDiffMode(t *testing.T) {
+	pt := newPrestateTracer(true)
+	addr := EthAddress{0x01}
+	slot := EthHash{0x01}
+
+	pt.RecordRead(addr, big.NewInt(100), 0, nil, &slot, EthHash{0x01})
+	pt.RecordPost(addr, big.NewInt(90), 1, nil, &slot, EthHash{0x02})
+
+	result, err := pt.GetResult()
+	if err != nil {
+		t.Fatalf("GetResult: %v", err)
+	}
+
+	diff := result.(PrestateTracerDiffResult)
+	post, ok := diff.Post[addr]
+	if !ok {
+		t.Fatalf("expected post entry for %v", addr)
+	}
+	if post.Balance == nil || post.Balance.Int.Cmp(big.NewInt(90)) != 0 {
+		t.Fatalf("expected changed balance in diff, got %+v", post.Balance)
+	}
+	if post.Nonce == nil || *post.Nonce != 1 {
+		t.Fatalf("expected changed nonce in diff, got %+v", post.Nonce)
+	}
+	if post.Storage[slot] != (EthHash{0x02}) {
+		t.Fatalf("expected changed storage slot in diff, got %+v", post.Storage)
+	}
+
+	if _, ok := diff.Pre[addr]; !ok {
+		t.Fatalf("expected pre entry for changed account %v", addr)
+	}
+	if diff.Pre[addr].Balance == nil || diff.Pre[addr].Balance.Int.Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("expected pre entry to keep only the original value of the changed field, got %+v", diff.Pre[addr].Balance)
+	}
+}
+
+func TestPrestateTracerDiffModePrunesReadOnlyAccounts(t *testing.T) {
+	pt := newPrestateTracer(true)
+	readOnly := EthAddress{0x02}
+	written := EthAddress{0x03}
+	slot := EthHash{0x01}
+
+	// readOnly is merely read (e.g. a BALANCE check on a third party) and
+	// never appears in RecordPost.
+	pt.RecordRead(readOnly, big.NewInt(100), 0, nil, &slot, EthHash{0x01})
+
+	pt.RecordRead(written, big.NewInt(1), 0, nil, nil, EthHash{})
+	pt.RecordPost(written, big.NewInt(2), 0, nil, nil, EthHash{})
+
+	result, err := pt.GetResult()
+	if err != nil {
+		t.Fatalf("GetResult: %v", err)
+	}
+
+	diff := result.(PrestateTracerDiffResult)
+	if _, ok := diff.Pre[readOnly]; ok {
+		t.Fatalf("expected read-only account to be pruned from diff.Pre, got %+v", diff.Pre[readOnly])
+	}
+	if _, ok := diff.Post[readOnly]; ok {
+		t.Fatalf("expected read-only account to be pruned from diff.Post, got %+v", diff.Post[readOnly])
+	}
+	if _, ok := diff.Pre[written]; !ok {
+		t.Fatalf("expected written account to remain in diff.Pre")
+	}
+}
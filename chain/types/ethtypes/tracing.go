@@ -38,10 +38,36 @@ type OverrideAccount struct {
 	Balance   **EthBigInt          `json:"balance"`
 	State     *map[EthHash]EthHash `json:"state"`
 	StateDiff *map[EthHash]EthHash `json:"stateDiff"`
+
+	// MovePrecompileTo relocates the precompile implementation installed at
+	// this account's address to the given address for the duration of the
+	// call, freeing the original address for user code. Only meaningful when
+	// this account's address currently holds a precompile.
+	MovePrecompileTo *EthAddress `json:"movePrecompileToAddress,omitempty"`
+	// CodeHash lets a caller that already knows the desired code hash supply
+	// it directly, instead of having it recomputed from Code.
+	CodeHash *EthHash `json:"codeHash,omitempty"`
+}
+
+// StateOverride is the collection of overridden accounts, together with any
+// wholesale precompile injections or removals that apply to the call as a
+// whole.
+type StateOverride struct {
+	Accounts map[EthHash]OverrideAccount
+	// Precompiles allows installing or removing precompiles for the
+	// duration of the call, keyed by the address the precompile should be
+	// reachable at. A nil value removes any precompile normally installed
+	// at that address.
+	Precompiles map[EthAddress]*EthBytes
 }
 
-// StateOverride is the collection of overridden accounts.
-type StateOverride map[EthHash]OverrideAccount
+// LegacyOverrideNames controls whether BlockOverrides.UnmarshalJSON also
+// accepts the capitalized Go field names (Number, ..., Coinbase/Random/BaseFee)
+// used before Lotus aligned with the finalized execution-apis schema.
+// MarshalJSON always emits the finalized names. It exists to give existing
+// Lotus/Filecoin RPC clients one release to migrate to feeRecipient/
+// prevRandao/baseFeePerGas before the fallback is removed.
+var LegacyOverrideNames = true
 
 // BlockOverrides is a set of header fields to override.
 type BlockOverrides struct {
@@ -49,13 +75,132 @@ type BlockOverrides struct {
 	Difficulty *EthBigInt
 	Time       *EthUint64
 	GasLimit   *EthUint64
-	Coinbase   *EthAddress
-	Random     *EthHash
-	BaseFee    *EthUint64
+	// Coinbase is the block's fee recipient. Marshaled as feeRecipient to
+	// match the finalized execution-apis schema.
+	Coinbase *EthAddress
+	// Random is the block's PREVRANDAO value. Marshaled as prevRandao.
+	Random *EthHash
+	// BaseFee is the block's base fee. Marshaled as baseFeePerGas.
+	BaseFee *EthUint64
+}
+
+// blockOverridesJSON is the wire representation of BlockOverrides using the
+// finalized execution-apis field names.
+type blockOverridesJSON struct {
+	Number        *EthBigInt  `json:"number,omitempty"`
+	Difficulty    *EthBigInt  `json:"difficulty,omitempty"`
+	Time          *EthUint64  `json:"time,omitempty"`
+	GasLimit      *EthUint64  `json:"gasLimit,omitempty"`
+	FeeRecipient  *EthAddress `json:"feeRecipient,omitempty"`
+	PrevRandao    *EthHash    `json:"prevRandao,omitempty"`
+	BaseFeePerGas *EthUint64  `json:"baseFeePerGas,omitempty"`
+}
+
+// blockOverridesLegacyJSON is the pre-execution-apis wire representation,
+// still accepted on input when LegacyOverrideNames is set. Before this
+// field-name migration, BlockOverrides had no json tags at all, so it
+// marshaled using the capitalized Go field names below; all seven fields
+// need a fallback, not just the three that were renamed.
+type blockOverridesLegacyJSON struct {
+	Number     *EthBigInt  `json:"Number,omitempty"`
+	Difficulty *EthBigInt  `json:"Difficulty,omitempty"`
+	Time       *EthUint64  `json:"Time,omitempty"`
+	GasLimit   *EthUint64  `json:"GasLimit,omitempty"`
+	Coinbase   *EthAddress `json:"Coinbase,omitempty"`
+	Random     *EthHash    `json:"Random,omitempty"`
+	BaseFee    *EthUint64  `json:"BaseFee,omitempty"`
+}
+
+// MarshalJSON emits BlockOverrides using the finalized execution-apis field
+// names (feeRecipient, prevRandao, baseFeePerGas).
+func (diff BlockOverrides) MarshalJSON() ([]byte, error) {
+	return json.Marshal(blockOverridesJSON{
+		Number:        diff.Number,
+		Difficulty:    diff.Difficulty,
+		Time:          diff.Time,
+		GasLimit:      diff.GasLimit,
+		FeeRecipient:  diff.Coinbase,
+		PrevRandao:    diff.Random,
+		BaseFeePerGas: diff.BaseFee,
+	})
+}
+
+// UnmarshalJSON accepts both the finalized execution-apis field names and,
+// when LegacyOverrideNames is set, the original capitalized Go field names
+// (including the pre-rename Coinbase/Random/BaseFee). The finalized names
+// take precedence if both are present for a given field.
+func (diff *BlockOverrides) UnmarshalJSON(b []byte) error {
+	var v blockOverridesJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	// encoding/json matches keys case-insensitively, so legacy "Number",
+	// "Difficulty", "Time" and "GasLimit" keys also land in v (the later key
+	// winning). Take those four fields from their exact finalized keys only.
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	v.Number, v.Difficulty, v.Time, v.GasLimit = nil, nil, nil, nil
+	for key, dst := range map[string]interface{}{"number": &v.Number, "difficulty": &v.Difficulty, "time": &v.Time, "gasLimit": &v.GasLimit} {
+		if msg, ok := raw[key]; ok {
+			if err := json.Unmarshal(msg, dst); err != nil {
+				return err
+			}
+		}
+	}
+
+	diff.Number = v.Number
+	diff.Difficulty = v.Difficulty
+	diff.Time = v.Time
+	diff.GasLimit = v.GasLimit
+	diff.Coinbase = v.FeeRecipient
+	diff.Random = v.PrevRandao
+	diff.BaseFee = v.BaseFeePerGas
+
+	if LegacyOverrideNames {
+		var legacy blockOverridesLegacyJSON
+		if err := json.Unmarshal(b, &legacy); err != nil {
+			return err
+		}
+		if diff.Number == nil {
+			diff.Number = legacy.Number
+		}
+		if diff.Difficulty == nil {
+			diff.Difficulty = legacy.Difficulty
+		}
+		if diff.Time == nil {
+			diff.Time = legacy.Time
+		}
+		if diff.GasLimit == nil {
+			diff.GasLimit = legacy.GasLimit
+		}
+		if diff.Coinbase == nil {
+			diff.Coinbase = legacy.Coinbase
+		}
+		if diff.Random == nil {
+			diff.Random = legacy.Random
+		}
+		if diff.BaseFee == nil {
+			diff.BaseFee = legacy.BaseFee
+		}
+	}
+
+	return nil
 }
 
-// Apply overrides the given header fields into the given block context.
+// Apply overrides the given header fields into the given block context,
+// resolving BlockContext.Fork against DefaultForkConfig. Callers tracing a
+// chain with a different fork schedule, or who have their own ForkConfig to
+// hand, should use ApplyWithFork instead.
 func (diff *BlockOverrides) Apply(blockCtx *BlockContext) {
+	diff.ApplyWithFork(blockCtx, &DefaultForkConfig)
+}
+
+// ApplyWithFork is Apply, but resolves BlockContext.Fork against forks
+// instead of DefaultForkConfig.
+func (diff *BlockOverrides) ApplyWithFork(blockCtx *BlockContext, forks *ForkConfig) {
 	if diff == nil {
 		return
 	}
@@ -80,6 +225,9 @@ func (diff *BlockOverrides) Apply(blockCtx *BlockContext) {
 	if diff.BaseFee != nil {
 		blockCtx.BaseFee = new(big.Int).SetUint64((uint64)(*diff.BaseFee))
 	}
+	if diff.Time != nil {
+		blockCtx.Fork = forks.Resolve(blockCtx.Time)
+	}
 }
 
 type (
@@ -111,6 +259,12 @@ type BlockContext struct {
 	Difficulty  *big.Int   // Provides information for DIFFICULTY
 	BaseFee     *big.Int   // Provides information for BASEFEE
 	Random      *EthHash   // Provides information for PREVRANDAO
+
+	// Fork is the highest hardfork active at Time, under the ForkConfig last
+	// used to resolve it. It's kept in sync with Time by BlockOverrides.Apply
+	// so a traced call with a bumped timestamp exercises the right opcode
+	// semantics without the caller overriding the chain config separately.
+	Fork ForkID
 }
 
 // TxTraceResult is the result of a single transaction trace.